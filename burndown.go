@@ -0,0 +1,297 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"math"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BurndownPoint is the ideal-vs-actual remaining-estimate snapshot for a
+// milestone on a single business day.
+type BurndownPoint struct {
+	Date            time.Time
+	IdealRemaining  float64
+	ActualRemaining float64
+	ClosedCount     int
+}
+
+type Burndown struct {
+	Milestone     *milestone
+	Points        []BurndownPoint
+	Projected     time.Time
+	HasProjection bool
+}
+
+// attachBurndowns skips milestones missing a creation date or due date.
+func attachBurndowns(ms map[string]*milestone, now time.Time) {
+	for _, m := range ms {
+		m.Burndown = computeBurndown(m, now)
+	}
+}
+
+func computeBurndown(m *milestone, now time.Time) *Burndown {
+	if m.CreatedAt.IsZero() || m.DueOn == nil {
+		return nil
+	}
+
+	days := businessDays(m.CreatedAt, *m.DueOn)
+
+	if len(days) == 0 {
+		return nil
+	}
+
+	var total float64
+
+	for _, is := range m.Issues {
+		total += float64(is.Days)
+	}
+
+	cutoff := now
+	if cutoff.After(*m.DueOn) {
+		cutoff = *m.DueOn
+	}
+
+	bd := &Burndown{Milestone: m}
+
+	for i, day := range days {
+		if day.After(cutoff) {
+			break
+		}
+
+		ideal := total
+
+		if len(days) > 1 {
+			ideal = total * (1 - float64(i)/float64(len(days)-1))
+		}
+
+		var closedDays float64
+		var closedCount int
+
+		for _, is := range m.Issues {
+			if is.ClosedAt != nil && !is.ClosedAt.After(day) {
+				closedDays += float64(is.Days)
+				closedCount++
+			}
+		}
+
+		bd.Points = append(bd.Points, BurndownPoint{
+			Date:            day,
+			IdealRemaining:  ideal,
+			ActualRemaining: total - closedDays,
+			ClosedCount:     closedCount,
+		})
+	}
+
+	bd.Projected, bd.HasProjection = projectCompletion(m.Issues, total, now)
+
+	return bd
+}
+
+// businessDays returns every Mon-Fri between start and end, inclusive.
+func businessDays(start, end time.Time) []time.Time {
+	var days []time.Time
+
+	d := truncateToDay(start)
+	last := truncateToDay(end)
+
+	for !d.After(last) {
+		if wd := d.Weekday(); wd != time.Saturday && wd != time.Sunday {
+			days = append(days, d)
+		}
+
+		d = d.AddDate(0, 0, 1)
+	}
+
+	return days
+}
+
+func truncateToDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// projectCompletion extrapolates a linear fit over the last 14 days of
+// closures to the day remaining work hits zero.
+func projectCompletion(all issues, total float64, now time.Time) (time.Time, bool) {
+	const window = 14
+
+	start := truncateToDay(now).AddDate(0, 0, -(window - 1))
+
+	var xs, ys []float64
+
+	for i := 0; i < window; i++ {
+		day := start.AddDate(0, 0, i)
+
+		var closed float64
+
+		for _, is := range all {
+			if is.ClosedAt != nil && !is.ClosedAt.After(day) {
+				closed += float64(is.Days)
+			}
+		}
+
+		xs = append(xs, float64(i))
+		ys = append(ys, total-closed)
+	}
+
+	slope, intercept, ok := linearRegression(xs, ys)
+
+	if !ok || slope >= 0 {
+		return time.Time{}, false
+	}
+
+	daysFromStart := -intercept / slope
+
+	return start.AddDate(0, 0, int(math.Round(daysFromStart))), true
+}
+
+func linearRegression(xs, ys []float64) (slope, intercept float64, ok bool) {
+	n := float64(len(xs))
+
+	if n == 0 {
+		return 0, 0, false
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumXX += xs[i] * xs[i]
+	}
+
+	denom := n*sumXX - sumX*sumX
+
+	if denom == 0 {
+		return 0, 0, false
+	}
+
+	slope = (n*sumXY - sumX*sumY) / denom
+	intercept = (sumY - slope*sumX) / n
+
+	return slope, intercept, true
+}
+
+// StatusBadge reports on-track / at-risk / N days late.
+func (bd *Burndown) StatusBadge() string {
+	if !bd.HasProjection || bd.Milestone.DueOn == nil {
+		return "https://img.shields.io/badge/schedule-unknown-lightgrey.svg?style=flat-square"
+	}
+
+	lateDays := int(math.Ceil(bd.Projected.Sub(*bd.Milestone.DueOn).Hours() / 24))
+
+	switch {
+	case lateDays <= 0:
+		return "https://img.shields.io/badge/schedule-on--track-brightgreen.svg?style=flat-square"
+	case lateDays <= 7:
+		return "https://img.shields.io/badge/schedule-at--risk-yellow.svg?style=flat-square"
+	default:
+		return "https://img.shields.io/badge/schedule-" + url.QueryEscape(fmt.Sprintf("%dd late", lateDays)) + "-red.svg?style=flat-square"
+	}
+}
+
+// SparklineSVG renders the ideal (grey) and actual (green) curves.
+func (bd *Burndown) SparklineSVG() template.HTML {
+	if bd == nil || len(bd.Points) == 0 {
+		return ""
+	}
+
+	const w, h = 120, 24
+
+	var maxV float64
+
+	for _, p := range bd.Points {
+		if p.IdealRemaining > maxV {
+			maxV = p.IdealRemaining
+		}
+
+		if p.ActualRemaining > maxV {
+			maxV = p.ActualRemaining
+		}
+	}
+
+	if maxV == 0 {
+		maxV = 1
+	}
+
+	ideal := sparklinePoints(bd.Points, w, h, maxV, func(p BurndownPoint) float64 { return p.IdealRemaining })
+	actual := sparklinePoints(bd.Points, w, h, maxV, func(p BurndownPoint) float64 { return p.ActualRemaining })
+
+	svg := fmt.Sprintf(
+		`<svg width="%d" height="%d" viewBox="0 0 %d %d" xmlns="http://www.w3.org/2000/svg">`+
+			`<polyline fill="none" stroke="#ccc" stroke-width="1" points="%s" />`+
+			`<polyline fill="none" stroke="#2a7" stroke-width="1" points="%s" />`+
+			`</svg>`,
+		w, h, w, h, ideal, actual,
+	)
+
+	return template.HTML(svg)
+}
+
+func sparklinePoints(points []BurndownPoint, w, h int, maxV float64, val func(BurndownPoint) float64) string {
+	var b strings.Builder
+
+	n := maxInt(len(points)-1, 1)
+
+	for i, p := range points {
+		x := float64(i) / float64(n) * float64(w)
+		y := float64(h) - (val(p)/maxV)*float64(h)
+
+		if i > 0 {
+			b.WriteString(" ")
+		}
+
+		fmt.Fprintf(&b, "%.1f,%.1f", x, y)
+	}
+
+	return b.String()
+}
+
+func writeBurndownCSV(file string, ms map[string]*milestone, milestoneNames []string) error {
+	f, err := os.Create(file)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	cw := csv.NewWriter(f)
+
+	if err := cw.Write([]string{"milestone", "date", "ideal_remaining", "actual_remaining", "closed_count"}); err != nil {
+		return err
+	}
+
+	for _, name := range milestoneNames {
+		m, ok := ms[name]
+
+		if !ok || m.Burndown == nil {
+			continue
+		}
+
+		for _, p := range m.Burndown.Points {
+			err := cw.Write([]string{
+				m.Title,
+				p.Date.Format("2006-01-02"),
+				strconv.FormatFloat(p.IdealRemaining, 'f', 2, 64),
+				strconv.FormatFloat(p.ActualRemaining, 'f', 2, 64),
+				strconv.Itoa(p.ClosedCount),
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
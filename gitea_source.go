@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+)
+
+// giteaSource is the IssueSource backed by a self-hosted Gitea/Gogs instance.
+type giteaSource struct {
+	cl      *gitea.Client
+	baseURL string
+}
+
+func newGiteaSource(baseURL, tok string) (*giteaSource, error) {
+	cl, err := gitea.NewClient(baseURL, gitea.SetToken(tok))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &giteaSource{cl: cl, baseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+func (s *giteaSource) ListIssues(ctx context.Context, owner, repo string) ([]CanonicalIssue, error) {
+	var (
+		all  []CanonicalIssue
+		page = 1
+	)
+
+	for {
+		issues, err := s.cl.ListRepoIssues(owner, repo, gitea.ListIssueOption{
+			State: gitea.StateAll,
+			ListOptions: gitea.ListOptions{
+				Page:     page,
+				PageSize: 50,
+			},
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(issues) == 0 {
+			break
+		}
+
+		for _, is := range issues {
+			all = append(all, s.giteaToCanonical(owner, repo, is))
+		}
+
+		page++
+	}
+
+	return all, nil
+}
+
+func (s *giteaSource) giteaToCanonical(owner, repo string, is *gitea.Issue) CanonicalIssue {
+	ci := CanonicalIssue{
+		Number:    int(is.Index),
+		Title:     is.Title,
+		HTMLURL:   fmt.Sprintf("%s/%s/%s/issues/%d", s.baseURL, owner, repo, is.Index),
+		CreatedAt: is.Created,
+		UpdatedAt: is.Updated,
+	}
+
+	if is.Closed != nil {
+		ci.ClosedAt = is.Closed
+	}
+
+	if is.Milestone != nil {
+		ci.Milestone = &CanonicalMilestone{
+			Title:     is.Milestone.Title,
+			CreatedAt: is.Milestone.Created,
+			DueOn:     is.Milestone.Deadline,
+		}
+	}
+
+	if is.Assignee != nil {
+		ci.Assignee = &CanonicalUser{
+			Login:     is.Assignee.UserName,
+			HTMLURL:   s.baseURL + "/" + is.Assignee.UserName,
+			AvatarURL: is.Assignee.AvatarURL,
+		}
+	}
+
+	for _, lbl := range is.Labels {
+		ci.Labels = append(ci.Labels, lbl.Name)
+	}
+
+	return ci
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/github"
+	"github.com/gregjones/httpcache"
+	"github.com/gregjones/httpcache/diskcache"
+	"golang.org/x/oauth2"
+)
+
+// maxPerPage is the most issues GitHub will hand back per page; asking
+// for more just silently caps at this.
+const maxPerPage = 100
+
+const maxAbuseRetries = 5
+
+// githubSource is the IssueSource backed by github.com (or GitHub
+// Enterprise, via baseURL).
+type githubSource struct {
+	cl *github.Client
+}
+
+func newGithubSource(tok, baseURL, cacheDir string) (*githubSource, error) {
+	var transport http.RoundTripper = &oauth2.Transport{
+		Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: tok}),
+	}
+
+	if cacheDir != "" {
+		transport = &httpcache.Transport{
+			Transport:           transport,
+			Cache:               diskcache.New(cacheDir),
+			MarkCachedResponses: true,
+		}
+	}
+
+	httpClient := &http.Client{Transport: transport}
+
+	if baseURL == "" {
+		return &githubSource{cl: github.NewClient(httpClient)}, nil
+	}
+
+	cl, err := github.NewEnterpriseClient(baseURL, baseURL, httpClient)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &githubSource{cl: cl}, nil
+}
+
+func (s *githubSource) ListIssues(ctx context.Context, owner, repo string) ([]CanonicalIssue, error) {
+	var (
+		all  []CanonicalIssue
+		page = 1
+	)
+
+	for {
+		issues, resp, err := s.listPage(owner, repo, page)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range issues {
+			all = append(all, githubToCanonical(&issues[i]))
+		}
+
+		page = resp.NextPage
+
+		if page == 0 {
+			break
+		}
+
+		if resp.Rate.Remaining == 0 && !resp.Rate.Reset.Time.IsZero() {
+			time.Sleep(time.Until(resp.Rate.Reset.Time))
+		}
+	}
+
+	return all, nil
+}
+
+// listPage retries on both abuse and primary rate-limit errors.
+func (s *githubSource) listPage(owner, repo string, page int) ([]github.Issue, *github.Response, error) {
+	backoff := time.Second
+
+	for try := 0; ; try++ {
+		issues, resp, err := s.cl.Issues.ListByRepo(owner, repo, &github.IssueListByRepoOptions{
+			State: "all",
+			ListOptions: github.ListOptions{
+				Page:    page,
+				PerPage: maxPerPage,
+			},
+		})
+
+		if abuseErr, ok := err.(*github.AbuseRateLimitError); ok && try < maxAbuseRetries {
+			wait := backoff
+
+			if abuseErr.RetryAfter != nil {
+				wait = *abuseErr.RetryAfter
+			}
+
+			time.Sleep(wait)
+			backoff *= 2
+			continue
+		}
+
+		if rlErr, ok := err.(*github.RateLimitError); ok && try < maxAbuseRetries {
+			time.Sleep(time.Until(rlErr.Rate.Reset.Time))
+			continue
+		}
+
+		return issues, resp, err
+	}
+}
+
+func githubToCanonical(ghIssue *github.Issue) CanonicalIssue {
+	ci := CanonicalIssue{
+		Title:   ghIssue.GetTitle(),
+		HTMLURL: ghIssue.GetHTMLURL(),
+	}
+
+	if ghIssue.Number != nil {
+		ci.Number = *ghIssue.Number
+	}
+
+	if ghIssue.CreatedAt != nil {
+		ci.CreatedAt = *ghIssue.CreatedAt
+	}
+
+	if ghIssue.UpdatedAt != nil {
+		ci.UpdatedAt = *ghIssue.UpdatedAt
+	}
+
+	ci.ClosedAt = ghIssue.ClosedAt
+
+	if ghIssue.Milestone != nil {
+		ci.Milestone = &CanonicalMilestone{
+			Title:     ghIssue.Milestone.GetTitle(),
+			CreatedAt: ghIssue.Milestone.GetCreatedAt(),
+			DueOn:     ghIssue.Milestone.DueOn,
+		}
+	}
+
+	if ghIssue.Assignee != nil {
+		ci.Assignee = &CanonicalUser{
+			Login:     ghIssue.Assignee.GetLogin(),
+			HTMLURL:   ghIssue.Assignee.GetHTMLURL(),
+			AvatarURL: ghIssue.Assignee.GetAvatarURL(),
+		}
+	}
+
+	for j := range ghIssue.Labels {
+		ci.Labels = append(ci.Labels, ghIssue.Labels[j].String())
+	}
+
+	return ci
+}
@@ -1,11 +1,10 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
-	"github.com/google/go-github/github"
-	"golang.org/x/oauth2"
 	"html/template"
 	"io/ioutil"
 	"log"
@@ -14,14 +13,24 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var (
-	tok        = flag.String("token", "", "")
-	repo       = flag.String("repo", "", "")
-	milestones = flag.String("milestones", "", "")
-	out        = flag.String("write-issues", "", "")
-	fromFile   = flag.String("from-file", "", "")
+	tok           = flag.String("token", "", "")
+	repo          = flag.String("repo", "", "")
+	milestones    = flag.String("milestones", "", "")
+	out           = flag.String("write-issues", "", "")
+	fromFile      = flag.String("from-file", "", "")
+	backend       = flag.String("backend", "github", "issue source backend: github or gitea")
+	baseURL       = flag.String("base-url", "", "base URL of a self-hosted instance: gitea/gogs, or GitHub Enterprise with -backend=github")
+	cacheDir      = flag.String("cache-dir", "", "directory to cache HTTP responses in between runs (github backend only)")
+	serveAddr     = flag.String("serve", "", "if set, serve a live dashboard on this address (e.g. :8080) instead of a one-shot report")
+	refresh       = flag.Duration("refresh", 5*time.Minute, "how often the dashboard re-fetches issues in -serve mode")
+	webhookSecret = flag.String("webhook-secret", "", "secret used to validate GitHub webhook deliveries at /webhook in -serve mode")
+	mode          = flag.String("mode", "table", "report to print: table or triage")
+	stale         = flag.String("stale", "30d", "how long without activity before an issue is considered stale, e.g. 30d, 2w")
+	burndownCSV   = flag.String("burndown-csv", "", "write per-milestone burndown data (milestone,date,ideal_remaining,actual_remaining,closed_count) to this CSV file")
 )
 
 func main() {
@@ -35,82 +44,79 @@ func run() error {
 	flag.Parse()
 
 	var (
-		cl     = client(*tok)
 		ms     = strings.Split(*milestones, ",")
 		err    error
-		issues []github.Issue
+		issues []CanonicalIssue
 	)
 
+	if *serveAddr != "" {
+		src, err := newSource(*backend, *tok, *baseURL, *cacheDir)
+
+		if err != nil {
+			return err
+		}
+
+		repoParts := strings.SplitN(*repo, "/", 2)
+
+		return serve(*serveAddr, src, repoParts[0], repoParts[1], ms, *refresh, *webhookSecret)
+	}
+
 	if *fromFile != "" {
 		issues, err = readIssues(*fromFile)
 	} else {
+		var src IssueSource
+
+		if src, err = newSource(*backend, *tok, *baseURL, *cacheDir); err != nil {
+			return err
+		}
+
 		repoParts := strings.SplitN(*repo, "/", 2)
-		issues, err = allIssues(cl, repoParts[0], repoParts[1])
+		issues, err = src.ListIssues(context.Background(), repoParts[0], repoParts[1])
 	}
 
 	if err != nil {
 		return err
 	}
 
-	if err = printIssues(issues, ms); err != nil {
+	staleAfter, err := parseStaleDuration(*stale)
+
+	if err != nil {
 		return err
 	}
 
-	if *out != "" {
-		if err = writeIssues(issues, *out); err != nil {
+	msMap, triage := parseIssues(issues, staleAfter)
+
+	if *mode == "triage" {
+		if err = printTriage(os.Stdout, triage); err != nil {
 			return err
 		}
-	}
-
-	return nil
-}
-
-func client(tok string) *github.Client {
-	var (
-		src    = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: tok})
-		httpCl = oauth2.NewClient(oauth2.NoContext, src)
-	)
-
-	return github.NewClient(httpCl)
-}
-
-func allIssues(client *github.Client, owner, repo string) ([]github.Issue, error) {
-	var (
-		allIssues []github.Issue
-		page      = 1
-	)
-
-	for {
-		issues, resp, err := client.Issues.ListByRepo(owner, repo, &github.IssueListByRepoOptions{
-			State: "all",
-			ListOptions: github.ListOptions{
-				Page:    page,
-				PerPage: 1000,
-			},
-		})
+	} else {
+		attachBurndowns(msMap, time.Now())
 
-		if err != nil {
-			return nil, err
+		if err = printMilestones(msMap, ms); err != nil {
+			return err
 		}
 
-		allIssues = append(allIssues, issues...)
-		page = resp.NextPage
+		if *burndownCSV != "" {
+			if err = writeBurndownCSV(*burndownCSV, msMap, ms); err != nil {
+				return err
+			}
+		}
+	}
 
-		if page == 0 {
-			break
+	if *out != "" {
+		if err = writeIssues(issues, *out); err != nil {
+			return err
 		}
 	}
 
-	return allIssues, nil
+	return nil
 }
 
-func printIssues(issues []github.Issue, milestones []string) error {
-	var (
-		ms = parseIssues(issues)
-		sl = []*milestone{}
-	)
+func printMilestones(ms map[string]*milestone, milestoneNames []string) error {
+	sl := []*milestone{}
 
-	for _, name := range milestones {
+	for _, name := range milestoneNames {
 		if _, ok := ms[name]; ok {
 			sl = append(sl, ms[name])
 		}
@@ -121,30 +127,15 @@ func printIssues(issues []github.Issue, milestones []string) error {
 	})
 }
 
-func parseIssues(ghIssues []github.Issue) map[string]*milestone {
+func parseIssues(ciIssues []CanonicalIssue, staleAfter time.Duration) (map[string]*milestone, *TriageBuckets) {
 	var (
 		msMap    = map[string]*milestone{}
 		msCmpMap = map[*milestone]map[string]*component{}
+		triage   = &TriageBuckets{}
 	)
 
-	for i := range ghIssues {
-		ghIssue := &ghIssues[i]
-
-		if ghIssue.Milestone == nil {
-			continue
-		}
-
-		ms := msMap[*ghIssue.Milestone.Title]
-
-		if ms == nil {
-			ms = &milestone{
-				Milestone:  ghIssue.Milestone,
-				Components: components{},
-			}
-
-			msMap[*ms.Title] = ms
-			msCmpMap[ms] = map[string]*component{}
-		}
+	for i := range ciIssues {
+		ciIssue := &ciIssues[i]
 
 		var (
 			cmpName  string
@@ -152,8 +143,8 @@ func parseIssues(ghIssues []github.Issue) map[string]*milestone {
 			days     int
 		)
 
-		for j := range ghIssue.Labels {
-			lbl := ghIssue.Labels[j].String()
+		for j := range ciIssue.Labels {
+			lbl := ciIssue.Labels[j]
 
 			if strings.HasPrefix(lbl, "component: ") {
 				cmpName = lbl[11:]
@@ -185,8 +176,29 @@ func parseIssues(ghIssues []github.Issue) map[string]*milestone {
 			}
 		}
 
+		is := &issue{CanonicalIssue: ciIssue, Type: typeName, Component: cmpName, Days: days}
+		classifyTriage(triage, is, cmpName, staleAfter)
+
+		if ciIssue.Milestone == nil {
+			continue
+		}
+
+		ms := msMap[ciIssue.Milestone.Title]
+
+		if ms == nil {
+			ms = &milestone{
+				Milestone:  ciIssue.Milestone,
+				Components: components{},
+			}
+
+			msMap[ms.Title] = ms
+			msCmpMap[ms] = map[string]*component{}
+		}
+
+		ms.Issues = append(ms.Issues, is)
+
 		ms.Stats.Total++
-		if ghIssue.ClosedAt != nil {
+		if ciIssue.ClosedAt != nil {
 			ms.Stats.Closed++
 		} else {
 			ms.Stats.Days += days
@@ -205,15 +217,10 @@ func parseIssues(ghIssues []github.Issue) map[string]*milestone {
 				ms.Components = append(ms.Components, cmp)
 			}
 
-			cmp.Issues = append(cmp.Issues, &issue{
-				Issue: ghIssue,
-				Type:  typeName,
-				Days:  days,
-			})
-
+			cmp.Issues = append(cmp.Issues, is)
 			cmp.Stats.Total++
 
-			if ghIssue.ClosedAt != nil {
+			if ciIssue.ClosedAt != nil {
 				cmp.Stats.Closed++
 			} else {
 				cmp.Stats.Days += days
@@ -229,10 +236,10 @@ func parseIssues(ghIssues []github.Issue) map[string]*milestone {
 		sort.Sort(ms.Components)
 	}
 
-	return msMap
+	return msMap, triage
 }
 
-func writeIssues(issues []github.Issue, file string) error {
+func writeIssues(issues []CanonicalIssue, file string) error {
 	js, err := json.Marshal(issues)
 
 	if err != nil {
@@ -242,14 +249,14 @@ func writeIssues(issues []github.Issue, file string) error {
 	return ioutil.WriteFile(file, js, 0666)
 }
 
-func readIssues(file string) ([]github.Issue, error) {
+func readIssues(file string) ([]CanonicalIssue, error) {
 	b, err := ioutil.ReadFile(file)
 
 	if err != nil {
 		return nil, err
 	}
 
-	var issues []github.Issue
+	var issues []CanonicalIssue
 	if err := json.Unmarshal(b, &issues); err != nil {
 		return nil, err
 	}
@@ -258,8 +265,10 @@ func readIssues(file string) ([]github.Issue, error) {
 }
 
 type milestone struct {
-	*github.Milestone
+	*CanonicalMilestone
 	Components components
+	Issues     issues
+	Burndown   *Burndown
 	Stats      struct {
 		Closed int
 		Total  int
@@ -294,9 +303,10 @@ func (c *component) DaysBadge() string {
 }
 
 type issue struct {
-	*github.Issue
-	Type string
-	Days int
+	*CanonicalIssue
+	Type      string
+	Component string
+	Days      int
 }
 
 type components []*component
@@ -319,7 +329,7 @@ func (sl issues) Less(i, j int) bool {
 
 	if iClosed == jClosed {
 		if iAssigned == jAssigned {
-			return *sl[i].Number < *sl[j].Number
+			return sl[i].Number < sl[j].Number
 		}
 
 		return !iAssigned
@@ -335,7 +345,7 @@ var tbl = template.Must(template.New("").Parse(`
 	<tbody>{{ range $i, $ms := .milestones }}
 		<tr>
 			<td colspan="6">
-				<h3>{{ $ms.Title }} <img hspace="5" align="right" src="{{ $ms.DaysBadge }}" /> <img hspace="5" align="right" src="{{ $ms.CompletedBadge }}" /></h3>
+				<h3><a href="/milestone?ms={{ $ms.Title }}">{{ $ms.Title }}</a> {{ if $ms.Burndown }}{{ $ms.Burndown.SparklineSVG }} <img hspace="5" align="right" src="{{ $ms.Burndown.StatusBadge }}" />{{ end }} <img hspace="5" align="right" src="{{ $ms.DaysBadge }}" /> <img hspace="5" align="right" src="{{ $ms.CompletedBadge }}" /></h3>
 			</td>
 		</tr>{{ range $j, $cmp := $ms.Components }}
 		<tr>
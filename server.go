@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/github"
+)
+
+const issuesPerPage = 25
+
+// dashboard holds the parsed milestone tree behind an RWMutex so the
+// background refresh loop and the HTTP handlers can share it safely.
+type dashboard struct {
+	src        IssueSource
+	owner      string
+	repo       string
+	milestones []string
+
+	mu     sync.RWMutex
+	ms     map[string]*milestone
+	issues []CanonicalIssue
+}
+
+func newDashboard(src IssueSource, owner, repo string, milestones []string) *dashboard {
+	return &dashboard{src: src, owner: owner, repo: repo, milestones: milestones}
+}
+
+func (d *dashboard) refresh(ctx context.Context) error {
+	issues, err := d.src.ListIssues(ctx, d.owner, d.repo)
+
+	if err != nil {
+		return err
+	}
+
+	ms, _ := parseIssues(issues, 0)
+	attachBurndowns(ms, time.Now())
+
+	d.mu.Lock()
+	d.issues = issues
+	d.ms = ms
+	d.mu.Unlock()
+
+	return nil
+}
+
+func (d *dashboard) refreshLoop(ctx context.Context, interval time.Duration) {
+	for {
+		if err := d.refresh(ctx); err != nil {
+			log.Printf("ERROR: refresh: %s\n", err)
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// mergeIssue folds a single updated issue in without waiting for the
+// next scheduled refresh.
+func (d *dashboard) mergeIssue(ci CanonicalIssue) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i := range d.issues {
+		if d.issues[i].Number == ci.Number {
+			d.issues[i] = ci
+			d.ms, _ = parseIssues(d.issues, 0)
+			attachBurndowns(d.ms, time.Now())
+			return
+		}
+	}
+
+	d.issues = append(d.issues, ci)
+	d.ms, _ = parseIssues(d.issues, 0)
+	attachBurndowns(d.ms, time.Now())
+}
+
+func (d *dashboard) serveIndex(w http.ResponseWriter, r *http.Request) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var sl []*milestone
+
+	for _, name := range d.milestones {
+		if ms, ok := d.ms[name]; ok {
+			sl = append(sl, ms)
+		}
+	}
+
+	if err := tbl.Execute(w, map[string]interface{}{
+		"milestones": sl,
+	}); err != nil {
+		log.Printf("ERROR: render index: %s\n", err)
+	}
+}
+
+func (d *dashboard) serveMilestone(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("ms")
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	ms := d.ms[name]
+
+	if ms == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var all issues
+
+	for _, cmp := range ms.Components {
+		all = append(all, cmp.Issues...)
+	}
+
+	offset, count := pagingParams(r)
+
+	end := offset + count
+	if end > len(all) {
+		end = len(all)
+	}
+	if offset > len(all) {
+		offset = len(all)
+	}
+
+	if err := msTbl.Execute(w, map[string]interface{}{
+		"milestone":  ms,
+		"components": groupByComponent(ms, all[offset:end]),
+		"hasPrev":    offset > 0,
+		"hasNext":    end < len(all),
+		"prevOffset": maxInt(0, offset-count),
+		"nextOffset": end,
+		"count":      count,
+	}); err != nil {
+		log.Printf("ERROR: render milestone: %s\n", err)
+	}
+}
+
+type pageComponent struct {
+	*component
+	PageIssues issues
+}
+
+// groupByComponent re-groups a page of a milestone's issues back into
+// per-component buckets, matching tbl's grouping instead of one flat list.
+func groupByComponent(ms *milestone, page issues) []pageComponent {
+	byName := map[string]*component{}
+
+	for _, cmp := range ms.Components {
+		byName[cmp.Name] = cmp
+	}
+
+	var groups []pageComponent
+
+	for _, is := range page {
+		if len(groups) == 0 || groups[len(groups)-1].Name != is.Component {
+			groups = append(groups, pageComponent{component: byName[is.Component]})
+		}
+
+		groups[len(groups)-1].PageIssues = append(groups[len(groups)-1].PageIssues, is)
+	}
+
+	return groups
+}
+
+func (d *dashboard) serveIssuesJSON(w http.ResponseWriter, r *http.Request) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(d.issues); err != nil {
+		log.Printf("ERROR: encode issues.json: %s\n", err)
+	}
+}
+
+// serveWebhook accepts GitHub issues/issue_comment/milestone events
+// validated against -webhook-secret.
+func (d *dashboard) serveWebhook(secret string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if !validSignature(secret, r.Header.Get("X-Hub-Signature-256"), body) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		switch r.Header.Get("X-GitHub-Event") {
+		case "issues", "issue_comment":
+			var payload struct {
+				Issue github.Issue `json:"issue"`
+			}
+
+			if err := json.Unmarshal(body, &payload); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			d.mergeIssue(githubToCanonical(&payload.Issue))
+		case "milestone":
+			// Milestone-only events don't carry per-issue state to merge;
+			// the next scheduled refresh will pick up the change.
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func validSignature(secret, header string, body []byte) bool {
+	if secret == "" || !strings.HasPrefix(header, "sha256=") {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(strings.TrimPrefix(header, "sha256=")))
+}
+
+func pagingParams(r *http.Request) (offset, count int) {
+	offset, _ = strconv.Atoi(r.URL.Query().Get("o"))
+	count, err := strconv.Atoi(r.URL.Query().Get("n"))
+
+	if err != nil || count <= 0 {
+		count = issuesPerPage
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	return offset, count
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+func serve(addr string, src IssueSource, owner, repo string, milestones []string, refresh time.Duration, webhookSecret string) error {
+	d := newDashboard(src, owner, repo, milestones)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go d.refreshLoop(ctx, refresh)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", d.serveIndex)
+	mux.HandleFunc("/milestone", d.serveMilestone)
+	mux.HandleFunc("/issues.json", d.serveIssuesJSON)
+
+	if webhookSecret != "" {
+		mux.HandleFunc("/webhook", d.serveWebhook(webhookSecret))
+	}
+
+	log.Printf("serving dashboard on %s\n", addr)
+
+	return http.ListenAndServe(addr, mux)
+}
+
+var msTbl = template.Must(template.New("").Parse(`
+<h3>{{ .milestone.Title }} {{ if .milestone.Burndown }}{{ .milestone.Burndown.SparklineSVG }} <img hspace="5" align="right" src="{{ .milestone.Burndown.StatusBadge }}" />{{ end }} <img hspace="5" align="right" src="{{ .milestone.DaysBadge }}" /> <img hspace="5" align="right" src="{{ .milestone.CompletedBadge }}" /></h3>
+<table>
+	<tbody>{{ range $j, $grp := .components }}
+		<tr>
+			<td colspan="6">
+				<h6>{{ $grp.Name }} <img hspace="5" align="right" src="{{ $grp.DaysBadge }}" /> <img hspace="5" align="right" src="{{ $grp.CompletedBadge }}" /></h6>
+			</td>
+		</tr>{{ range $k, $issue := $grp.PageIssues }}
+		<tr>
+			<td><a href="{{ $issue.HTMLURL }}">#{{ $issue.Number }}</a></td>
+			<td><kbd>{{ $issue.Type }}</kbd></td>
+			<td>{{ if $issue.Days }}{{ $issue.Days }}d{{ end }}</td>
+			<td>{{ $issue.Title }}</td>
+			<td width="60">{{ if $issue.Assignee }}<a href="{{ $issue.Assignee.HTMLURL }}"><img valign="middle" height="30" width="30" src="{{ $issue.Assignee.AvatarURL }} " /></a>{{ end }}</td>
+			<td>{{ if $issue.ClosedAt }}☑️{{ end }}</td>
+		</tr>{{ end }}{{ end }}
+	</tbody>
+</table>
+<footer>{{ if .hasPrev }}<a href="?ms={{ .milestone.Title }}&o={{ .prevOffset }}&n={{ .count }}">&laquo; prev</a>{{ end }} {{ if .hasNext }}<a href="?ms={{ .milestone.Title }}&o={{ .nextOffset }}&n={{ .count }}">next &raquo;</a>{{ end }}</footer>
+`))
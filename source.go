@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CanonicalIssue is the backend-neutral shape parseIssues and the
+// templates operate on, regardless of whether it came from GitHub or Gitea.
+type CanonicalIssue struct {
+	Number    int
+	Title     string
+	HTMLURL   string
+	Labels    []string
+	Milestone *CanonicalMilestone
+	Assignee  *CanonicalUser
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ClosedAt  *time.Time
+}
+
+type CanonicalMilestone struct {
+	Title     string
+	CreatedAt time.Time
+	DueOn     *time.Time
+}
+
+type CanonicalUser struct {
+	Login     string
+	HTMLURL   string
+	AvatarURL string
+}
+
+// IssueSource fetches issues for a repo from some backend (GitHub, Gitea,
+// ...) in the CanonicalIssue shape.
+type IssueSource interface {
+	ListIssues(ctx context.Context, owner, repo string) ([]CanonicalIssue, error)
+}
+
+func newSource(backend, tok, baseURL, cacheDir string) (IssueSource, error) {
+	switch backend {
+	case "", "github":
+		return newGithubSource(tok, baseURL, cacheDir)
+	case "gitea":
+		return newGiteaSource(baseURL, tok)
+	default:
+		return nil, fmt.Errorf("unknown backend %q", backend)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TriageBuckets mirrors the categories the golang/go issue dashboard
+// sorts open issues into.
+type TriageBuckets struct {
+	Unassigned  issues
+	NoMilestone issues
+	Stale       issues
+	NeedsTriage issues
+	InProgress  issues
+}
+
+func (i *issue) Age() time.Duration {
+	return time.Since(i.CreatedAt)
+}
+
+func (i *issue) IdleFor() time.Duration {
+	last := i.UpdatedAt
+
+	if last.IsZero() {
+		last = i.CreatedAt
+	}
+
+	return time.Since(last)
+}
+
+// classifyTriage buckets a single open issue; closed issues are excluded.
+func classifyTriage(t *TriageBuckets, is *issue, cmpName string, staleAfter time.Duration) {
+	if is.ClosedAt != nil {
+		return
+	}
+
+	if is.Milestone == nil {
+		t.NoMilestone = append(t.NoMilestone, is)
+	}
+
+	if is.Assignee == nil {
+		t.Unassigned = append(t.Unassigned, is)
+	} else {
+		t.InProgress = append(t.InProgress, is)
+	}
+
+	if cmpName == "" && is.Type == "" {
+		t.NeedsTriage = append(t.NeedsTriage, is)
+	}
+
+	if staleAfter > 0 && is.IdleFor() >= staleAfter {
+		t.Stale = append(t.Stale, is)
+	}
+}
+
+// parseStaleDuration accepts the "Nd"/"Nw" shorthand used by `estimate:`
+// labels, falling back to time.ParseDuration (e.g. "12h").
+func parseStaleDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+
+	if s == "" {
+		return 0, nil
+	}
+
+	if strings.HasSuffix(s, "w") {
+		n, err := strconv.Atoi(s[:len(s)-1])
+
+		if err != nil {
+			return 0, err
+		}
+
+		return time.Duration(n) * 7 * 24 * time.Hour, nil
+	}
+
+	if strings.HasSuffix(s, "d") {
+		n, err := strconv.Atoi(s[:len(s)-1])
+
+		if err != nil {
+			return 0, err
+		}
+
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(s)
+}
+
+func printTriage(w io.Writer, buckets *TriageBuckets) error {
+	return triageTpl.Execute(w, buckets)
+}
+
+func formatAge(d time.Duration) string {
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+var triageTpl = template.Must(template.New("").Funcs(template.FuncMap{
+	"formatAge": formatAge,
+}).Parse(`## Needs triage
+{{ range $i, $is := .NeedsTriage }}- #{{ $is.Number }} {{ $is.Title }}{{ if $is.Assignee }} @{{ $is.Assignee.Login }}{{ end }} [{{ range $is.Labels }}{{ . }} {{ end }}] ({{ formatAge $is.Age }})
+{{ end }}
+## Unassigned
+{{ range $i, $is := .Unassigned }}- #{{ $is.Number }} {{ $is.Title }} [{{ range $is.Labels }}{{ . }} {{ end }}] ({{ formatAge $is.Age }})
+{{ end }}
+## No milestone
+{{ range $i, $is := .NoMilestone }}- #{{ $is.Number }} {{ $is.Title }}{{ if $is.Assignee }} @{{ $is.Assignee.Login }}{{ end }} [{{ range $is.Labels }}{{ . }} {{ end }}] ({{ formatAge $is.Age }})
+{{ end }}
+## Stale
+{{ range $i, $is := .Stale }}- #{{ $is.Number }} {{ $is.Title }}{{ if $is.Assignee }} @{{ $is.Assignee.Login }}{{ end }} [{{ range $is.Labels }}{{ . }} {{ end }}] (idle {{ formatAge $is.IdleFor }})
+{{ end }}
+## In progress
+{{ range $i, $is := .InProgress }}- #{{ $is.Number }} {{ $is.Title }} @{{ $is.Assignee.Login }} [{{ range $is.Labels }}{{ . }} {{ end }}] ({{ formatAge $is.Age }})
+{{ end }}
+`))